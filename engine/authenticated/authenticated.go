@@ -0,0 +1,102 @@
+// Package authenticated layers a sparse Merkle tree on top of any engine.Store so that every
+// key value pair it holds can be proven to be included in, or absent from, a given root hash
+// without access to the rest of the data. This makes it possible to back light-client or
+// verifiable-query use cases with Genji.
+package authenticated
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// AlgorithmSHA256 identifies proofs produced with SHA-256 as the hash function, which is
+// currently the only one this package supports.
+const AlgorithmSHA256 = 1
+
+// smtDepth is the depth of the tree, one level per bit of a SHA-256 digest.
+const smtDepth = 256
+
+// nodesSuffix is appended to a store's name to obtain the name of the store holding its tree
+// nodes.
+const nodesSuffix = "__smt"
+
+// defaultHash[d] is the hash of an empty subtree rooted at depth d. defaultHash[smtDepth] is the
+// hash of an absent leaf; defaultHash[0] is the root hash of a completely empty tree.
+var defaultHash [smtDepth + 1][]byte
+
+func init() {
+	defaultHash[smtDepth] = hashLeaf(nil)
+	for d := smtDepth - 1; d >= 0; d-- {
+		defaultHash[d] = hashNode(defaultHash[d+1], defaultHash[d+1])
+	}
+}
+
+func hashLeaf(b []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(b)
+	return h.Sum(nil)
+}
+
+func hashNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// leafHash binds a key's path and value together so that two different keys mapping to the same
+// value never produce the same leaf hash.
+func leafHash(path, value []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(path)
+	h.Write(value)
+	return h.Sum(nil)
+}
+
+// bitAt returns the bit of path at the given position, counting from the most significant bit of
+// the first byte.
+func bitAt(path []byte, pos int) byte {
+	return (path[pos/8] >> uint(7-pos%8)) & 1
+}
+
+// withBit returns a copy of path with the bit at pos set to the given value.
+func withBit(path []byte, pos int, bit byte) []byte {
+	cp := make([]byte, len(path))
+	copy(cp, path)
+
+	mask := byte(1) << uint(7-pos%8)
+	if bit == 0 {
+		cp[pos/8] &^= mask
+	} else {
+		cp[pos/8] |= mask
+	}
+
+	return cp
+}
+
+// packPrefix returns the first nbits bits of path, packed into ceil(nbits/8) bytes with the
+// unused bits of the last byte zeroed.
+func packPrefix(path []byte, nbits int) []byte {
+	nbytes := (nbits + 7) / 8
+	prefix := make([]byte, nbytes)
+	copy(prefix, path[:nbytes])
+
+	if rem := nbits % 8; rem != 0 {
+		prefix[nbytes-1] &= byte(0xFF << uint(8-rem))
+	}
+
+	return prefix
+}
+
+// nodeKey is the key under which the hash of the node at the given depth and prefix is stored in
+// the tree's node store.
+func nodeKey(depth int, prefix []byte) []byte {
+	return append([]byte{byte(depth >> 8), byte(depth)}, prefix...)
+}
+
+func isDefault(depth int, h []byte) bool {
+	return bytes.Equal(h, defaultHash[depth])
+}
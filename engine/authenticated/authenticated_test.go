@@ -0,0 +1,123 @@
+package authenticated_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/asdine/genji/engine/authenticated"
+	"github.com/asdine/genji/engine/fsdb"
+	"github.com/stretchr/testify/require"
+)
+
+func tempEngine(t *testing.T) (*fsdb.Engine, func()) {
+	dir, err := ioutil.TempDir("", "genji-authenticated")
+	require.NoError(t, err)
+
+	ng, err := fsdb.NewEngine(dir)
+	require.NoError(t, err)
+
+	return ng, func() {
+		ng.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestRootChangesOnWrite(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	require.NoError(t, tx.CreateStore("table1"))
+
+	st, err := authenticated.Wrap(tx, "table1")
+	require.NoError(t, err)
+
+	empty, err := st.Root()
+	require.NoError(t, err)
+
+	require.NoError(t, st.Put([]byte("foo"), []byte("bar")))
+	afterPut, err := st.Root()
+	require.NoError(t, err)
+	require.NotEqual(t, empty, afterPut)
+
+	require.NoError(t, st.Delete([]byte("foo")))
+	afterDelete, err := st.Root()
+	require.NoError(t, err)
+	require.Equal(t, empty, afterDelete)
+}
+
+func TestWrapReadOnlyBeforeAnyWrite(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	require.NoError(t, tx.Commit())
+
+	// table1 exists but nothing has ever written through authenticated.Wrap, so its __smt
+	// side store doesn't exist yet; wrapping it from a read-only transaction must still work
+	// and report the empty tree's root rather than failing because it can't create a store.
+	tx, err = ng.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	st, err := authenticated.Wrap(tx, "table1")
+	require.NoError(t, err)
+
+	root, err := st.Root()
+	require.NoError(t, err)
+	require.NotNil(t, root)
+
+	v, proof, err := st.Prove([]byte("foo"))
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	ok, err := authenticated.Verify(root, []byte("foo"), nil, proof)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestProveVerify(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := authenticated.Wrap(tx, "table1")
+	require.NoError(t, err)
+
+	require.NoError(t, st.Put([]byte("foo"), []byte("bar")))
+	require.NoError(t, st.Put([]byte("baz"), []byte("qux")))
+
+	root, err := st.Root()
+	require.NoError(t, err)
+
+	v, proof, err := st.Prove([]byte("foo"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), v)
+
+	ok, err := authenticated.Verify(root, []byte("foo"), []byte("bar"), proof)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = authenticated.Verify(root, []byte("foo"), []byte("wrong"), proof)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// exclusion proof for a key that was never set.
+	v, proof, err = st.Prove([]byte("absent"))
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	ok, err = authenticated.Verify(root, []byte("absent"), nil, proof)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -0,0 +1,215 @@
+package authenticated
+
+import (
+	"crypto/sha256"
+
+	"github.com/asdine/genji/engine"
+)
+
+// Store wraps an engine.Store with a sparse Merkle tree, so that the set of key value pairs it
+// holds can be summarized by a single root hash and individual keys can be proven included in, or
+// absent from, that root.
+type Store struct {
+	data  engine.Store
+	nodes engine.Store
+}
+
+// Wrap returns a Store that authenticates name's content using tx. It opens the regular store
+// plus a second one, name+"__smt", used to persist the tree nodes; the latter is created on
+// demand the first time a writable transaction touches it. A read-only transaction never creates
+// it: if it doesn't exist yet, the tree is simply treated as empty, since there are no writes to
+// persist nodes for anyway.
+func Wrap(tx engine.Transaction, name string) (*Store, error) {
+	data, err := tx.Store(name)
+	if err != nil {
+		return nil, err
+	}
+
+	nodesName := name + nodesSuffix
+	nodes, err := tx.Store(nodesName)
+	if err == engine.ErrStoreNotFound {
+		if err := tx.CreateStore(nodesName); err != nil {
+			if err == engine.ErrTransactionReadOnly {
+				return &Store{data: data, nodes: nil}, nil
+			}
+			return nil, err
+		}
+		nodes, err = tx.Store(nodesName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{data: data, nodes: nodes}, nil
+}
+
+// Root returns the current root hash of the tree. An empty store has a stable, non-nil root.
+func (s *Store) Root() ([]byte, error) {
+	return s.getNode(0, nil)
+}
+
+// Get returns the value associated with k. If the key is not found, it returns
+// engine.ErrKeyNotFound.
+func (s *Store) Get(k []byte) ([]byte, error) {
+	return s.data.Get(k)
+}
+
+// Put stores a key value pair, overriding any existing value, and updates the tree along the
+// key's path.
+func (s *Store) Put(k, v []byte) error {
+	if err := s.data.Put(k, v); err != nil {
+		return err
+	}
+
+	path := sha256.Sum256(k)
+	return s.update(path[:], leafHash(path[:], v))
+}
+
+// Delete removes a key value pair and updates the tree along the key's path. Returns
+// engine.ErrKeyNotFound if the key doesn't exist.
+func (s *Store) Delete(k []byte) error {
+	if err := s.data.Delete(k); err != nil {
+		return err
+	}
+
+	path := sha256.Sum256(k)
+	return s.update(path[:], defaultHash[smtDepth])
+}
+
+// Truncate deletes all the key value pairs from the store and resets the tree to its empty state.
+func (s *Store) Truncate() error {
+	if err := s.data.Truncate(); err != nil {
+		return err
+	}
+
+	return s.nodes.Truncate()
+}
+
+// AscendGreaterOrEqual delegates to the underlying store; iteration order is unaffected by the
+// tree layered on top.
+func (s *Store) AscendGreaterOrEqual(pivot []byte, fn func(k, v []byte) error) error {
+	return s.data.AscendGreaterOrEqual(pivot, fn)
+}
+
+// DescendLessOrEqual delegates to the underlying store; iteration order is unaffected by the tree
+// layered on top.
+func (s *Store) DescendLessOrEqual(pivot []byte, fn func(k, v []byte) error) error {
+	return s.data.DescendLessOrEqual(pivot, fn)
+}
+
+// AscendRange delegates to the underlying store; range bounds are unaffected by the tree layered
+// on top.
+func (s *Store) AscendRange(start, end []byte, fn func(k, v []byte) error) error {
+	return s.data.AscendRange(start, end, fn)
+}
+
+// DescendRange delegates to the underlying store; range bounds are unaffected by the tree layered
+// on top.
+func (s *Store) DescendRange(start, end []byte, fn func(k, v []byte) error) error {
+	return s.data.DescendRange(start, end, fn)
+}
+
+// AscendPrefix delegates to the underlying store; prefix matching is unaffected by the tree
+// layered on top.
+func (s *Store) AscendPrefix(prefix []byte, fn func(k, v []byte) error) error {
+	return s.data.AscendPrefix(prefix, fn)
+}
+
+// DescendPrefix delegates to the underlying store; prefix matching is unaffected by the tree
+// layered on top.
+func (s *Store) DescendPrefix(prefix []byte, fn func(k, v []byte) error) error {
+	return s.data.DescendPrefix(prefix, fn)
+}
+
+// update recomputes every node on the path from the leaf to the root after it changed to leaf.
+func (s *Store) update(path, leaf []byte) error {
+	if err := s.setNode(smtDepth, path, leaf); err != nil {
+		return err
+	}
+
+	cur := leaf
+	for d := smtDepth; d > 0; d-- {
+		bit := bitAt(path, d-1)
+		siblingPrefix := packPrefix(withBit(path, d-1, 1-bit), d)
+		sibling, err := s.getNode(d, siblingPrefix)
+		if err != nil {
+			return err
+		}
+
+		var parent []byte
+		if bit == 0 {
+			parent = hashNode(cur, sibling)
+		} else {
+			parent = hashNode(sibling, cur)
+		}
+
+		if err := s.setNode(d-1, packPrefix(path, d-1), parent); err != nil {
+			return err
+		}
+
+		cur = parent
+	}
+
+	return nil
+}
+
+func (s *Store) getNode(depth int, prefix []byte) ([]byte, error) {
+	// nodes is nil when Wrap opened a read-only transaction before any writable one ever
+	// populated the node store; the tree is then still in its default, empty state.
+	if s.nodes == nil {
+		return defaultHash[depth], nil
+	}
+
+	v, err := s.nodes.Get(nodeKey(depth, prefix))
+	if err == engine.ErrKeyNotFound {
+		return defaultHash[depth], nil
+	}
+
+	return v, err
+}
+
+func (s *Store) setNode(depth int, prefix []byte, h []byte) error {
+	key := nodeKey(depth, prefix)
+
+	if isDefault(depth, h) {
+		err := s.nodes.Delete(key)
+		if err == engine.ErrKeyNotFound {
+			return nil
+		}
+		return err
+	}
+
+	return s.nodes.Put(key, h)
+}
+
+// Prove returns the value currently stored for k (nil if it isn't present) together with a proof
+// that can be checked against a root hash with Verify. The proof is self-contained: its first
+// element encodes the hash algorithm and tree depth used to produce it, and the rest are the
+// sibling hashes along k's path, ordered from the leaf to the root.
+func (s *Store) Prove(k []byte) ([]byte, [][]byte, error) {
+	path := sha256.Sum256(k)
+
+	value, err := s.data.Get(k)
+	if err == engine.ErrKeyNotFound {
+		value, err = nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	depth := uint16(smtDepth)
+	proof := make([][]byte, 0, smtDepth+1)
+	proof = append(proof, []byte{AlgorithmSHA256, byte(depth >> 8), byte(depth)})
+
+	for d := smtDepth; d > 0; d-- {
+		bit := bitAt(path[:], d-1)
+		siblingPrefix := packPrefix(withBit(path[:], d-1, 1-bit), d)
+		sibling, err := s.getNode(d, siblingPrefix)
+		if err != nil {
+			return nil, nil, err
+		}
+		proof = append(proof, sibling)
+	}
+
+	return value, proof, nil
+}
@@ -0,0 +1,51 @@
+package authenticated
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+)
+
+// ErrInvalidProof is returned by Verify when proof isn't in the format produced by Prove, or uses
+// an algorithm or depth this package doesn't support.
+var ErrInvalidProof = errors.New("authenticated: invalid proof")
+
+// Verify reports whether proof, as returned by (*Store).Prove, demonstrates that key maps to
+// value under root. Passing a nil value checks an exclusion proof, i.e. that key is absent from
+// the tree summarized by root. Verify doesn't need access to the store that produced the proof.
+func Verify(root, key, value []byte, proof [][]byte) (bool, error) {
+	if len(proof) != smtDepth+1 {
+		return false, ErrInvalidProof
+	}
+
+	header := proof[0]
+	if len(header) != 3 || header[0] != AlgorithmSHA256 {
+		return false, ErrInvalidProof
+	}
+	depth := int(header[1])<<8 | int(header[2])
+	if depth != smtDepth {
+		return false, ErrInvalidProof
+	}
+
+	path := sha256.Sum256(key)
+
+	var cur []byte
+	if value == nil {
+		cur = defaultHash[smtDepth]
+	} else {
+		cur = leafHash(path[:], value)
+	}
+
+	for d := smtDepth; d > 0; d-- {
+		sibling := proof[smtDepth-d+1]
+		bit := bitAt(path[:], d-1)
+
+		if bit == 0 {
+			cur = hashNode(cur, sibling)
+		} else {
+			cur = hashNode(sibling, cur)
+		}
+	}
+
+	return bytes.Equal(cur, root), nil
+}
@@ -0,0 +1,50 @@
+// Package bolt implements an engine that uses bbolt, the actively maintained fork of BoltDB, as
+// the underlying storage.
+package bolt
+
+import (
+	"os"
+
+	"github.com/asdine/genji/engine"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Engine represents a bbolt engine. Each store is mapped to a Bolt bucket.
+type Engine struct {
+	DB *bolt.DB
+
+	path string
+	mode os.FileMode
+}
+
+// NewEngine creates a bbolt engine. It takes the same arguments as bolt.Open.
+func NewEngine(path string, mode os.FileMode, opts *bolt.Options) (*Engine, error) {
+	db, err := bolt.Open(path, mode, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Engine{
+		DB:   db,
+		path: path,
+		mode: mode,
+	}, nil
+}
+
+// Begin starts a transaction using Bolt's native read/write transactions.
+func (ng *Engine) Begin(writable bool) (engine.Transaction, error) {
+	tx, err := ng.DB.Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		tx:       tx,
+		writable: writable,
+	}, nil
+}
+
+// Close the engine and underlying Bolt database.
+func (ng *Engine) Close() error {
+	return ng.DB.Close()
+}
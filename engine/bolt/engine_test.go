@@ -0,0 +1,139 @@
+package bolt_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asdine/genji/engine"
+	"github.com/asdine/genji/engine/bolt"
+	"github.com/asdine/genji/engine/enginetest"
+	"github.com/stretchr/testify/require"
+)
+
+func tempEngine(t *testing.T) (*bolt.Engine, func()) {
+	dir, err := ioutil.TempDir("", "genji-bolt")
+	require.NoError(t, err)
+
+	ng, err := bolt.NewEngine(filepath.Join(dir, "genji.db"), 0600, nil)
+	require.NoError(t, err)
+
+	return ng, func() {
+		ng.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestEngineStores(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	err = tx.CreateStore("table1")
+	require.NoError(t, err)
+
+	err = tx.CreateStore("table1")
+	require.Equal(t, engine.ErrStoreAlreadyExists, err)
+
+	_, err = tx.Store("table2")
+	require.Equal(t, engine.ErrStoreNotFound, err)
+
+	err = tx.DropStore("table2")
+	require.Equal(t, engine.ErrStoreNotFound, err)
+
+	require.NoError(t, tx.Commit())
+}
+
+func TestStorePutGetDelete(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	err = tx.CreateStore("table1")
+	require.NoError(t, err)
+
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+
+	_, err = st.Get([]byte("foo"))
+	require.Equal(t, engine.ErrKeyNotFound, err)
+
+	err = st.Put([]byte("foo"), []byte("bar"))
+	require.NoError(t, err)
+
+	v, err := st.Get([]byte("foo"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), v)
+
+	err = st.Delete([]byte("foo"))
+	require.NoError(t, err)
+
+	_, err = st.Get([]byte("foo"))
+	require.Equal(t, engine.ErrKeyNotFound, err)
+
+	err = st.Delete([]byte("foo"))
+	require.Equal(t, engine.ErrKeyNotFound, err)
+}
+
+func TestStoreReadOnly(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	require.NoError(t, tx.Commit())
+
+	tx, err = ng.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	err = tx.CreateStore("table2")
+	require.Equal(t, engine.ErrTransactionReadOnly, err)
+
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+
+	err = st.Put([]byte("foo"), []byte("bar"))
+	require.Equal(t, engine.ErrTransactionReadOnly, err)
+
+	err = tx.Commit()
+	require.Equal(t, engine.ErrTransactionReadOnly, err)
+}
+
+func TestStoreAscendDescend(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+
+	enginetest.TestStoreAscendDescend(t, st)
+}
+
+func TestStoreRangeAndPrefix(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+
+	enginetest.TestStoreRangeAndPrefix(t, st)
+}
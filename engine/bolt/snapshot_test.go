@@ -0,0 +1,45 @@
+package bolt_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asdine/genji/engine/bolt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genji-bolt-snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ng, err := bolt.NewEngine(filepath.Join(dir, "genji.db"), 0600, nil)
+	require.NoError(t, err)
+	defer ng.Close()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+	require.NoError(t, st.Put([]byte("foo"), []byte("bar")))
+	require.NoError(t, tx.Commit())
+
+	var buf bytes.Buffer
+	require.NoError(t, ng.Snapshot(&buf))
+
+	require.NoError(t, ng.Restore(bytes.NewReader(buf.Bytes())))
+
+	tx, err = ng.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	st, err = tx.Store("table1")
+	require.NoError(t, err)
+	v, err := st.Get([]byte("foo"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), v)
+}
@@ -0,0 +1,211 @@
+package bolt
+
+import (
+	"bytes"
+
+	"github.com/asdine/genji/engine"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store wraps a Bolt bucket and implements the engine.Store interface.
+type Store struct {
+	bucket *bolt.Bucket
+	tx     *bolt.Tx
+}
+
+// Get returns the value of the given key. Returns engine.ErrKeyNotFound if the key doesn't exist.
+func (s *Store) Get(k []byte) ([]byte, error) {
+	v := s.bucket.Get(k)
+	if v == nil {
+		return nil, engine.ErrKeyNotFound
+	}
+
+	// the slice returned by Bolt is only valid for the lifetime of the transaction,
+	// copy it so it can be used after the transaction is closed.
+	cp := make([]byte, len(v))
+	copy(cp, v)
+	return cp, nil
+}
+
+// Put stores a key value pair. If it already exists, it overrides it.
+// Returns engine.ErrTransactionReadOnly if the transaction is read-only.
+func (s *Store) Put(k, v []byte) error {
+	if !s.tx.Writable() {
+		return engine.ErrTransactionReadOnly
+	}
+
+	return s.bucket.Put(k, v)
+}
+
+// Delete a key value pair. Returns engine.ErrKeyNotFound if the key doesn't exist
+// and engine.ErrTransactionReadOnly if the transaction is read-only.
+func (s *Store) Delete(k []byte) error {
+	if !s.tx.Writable() {
+		return engine.ErrTransactionReadOnly
+	}
+
+	if s.bucket.Get(k) == nil {
+		return engine.ErrKeyNotFound
+	}
+
+	return s.bucket.Delete(k)
+}
+
+// cloneKV copies k and v, since the slices Bolt's cursor yields are only valid for the lifetime
+// of the transaction and must not be retained by callers of the iteration methods below.
+func cloneKV(k, v []byte) ([]byte, []byte) {
+	ck := make([]byte, len(k))
+	copy(ck, k)
+	cv := make([]byte, len(v))
+	copy(cv, v)
+	return ck, cv
+}
+
+// Truncate deletes all the key value pairs from the store.
+func (s *Store) Truncate() error {
+	if !s.tx.Writable() {
+		return engine.ErrTransactionReadOnly
+	}
+
+	c := s.bucket.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.First() {
+		err := s.bucket.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AscendGreaterOrEqual seeks for the pivot and goes through all the subsequent key value pairs in
+// increasing order and calls fn for each pair. If pivot is nil, starts from the beginning.
+func (s *Store) AscendGreaterOrEqual(pivot []byte, fn func(k, v []byte) error) error {
+	c := s.bucket.Cursor()
+
+	var k, v []byte
+	if len(pivot) == 0 {
+		k, v = c.First()
+	} else {
+		k, v = c.Seek(pivot)
+	}
+
+	for ; k != nil; k, v = c.Next() {
+		ck, cv := cloneKV(k, v)
+		if err := fn(ck, cv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DescendLessOrEqual seeks for the pivot and goes through all the subsequent key value pairs in
+// decreasing order and calls fn for each pair. If pivot is nil, starts from the end.
+func (s *Store) DescendLessOrEqual(pivot []byte, fn func(k, v []byte) error) error {
+	c := s.bucket.Cursor()
+
+	var k, v []byte
+	if len(pivot) == 0 {
+		k, v = c.Last()
+	} else {
+		k, v = c.Seek(pivot)
+		if k == nil {
+			k, v = c.Last()
+		} else if !bytes.Equal(k, pivot) {
+			k, v = c.Prev()
+		}
+	}
+
+	for ; k != nil; k, v = c.Prev() {
+		ck, cv := cloneKV(k, v)
+		if err := fn(ck, cv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AscendRange seeks for start and goes through all the subsequent key value pairs whose key is
+// lesser than or equal to end, in increasing order, calling fn for each pair. If start is nil,
+// starts from the beginning. If end is nil, there is no upper bound.
+func (s *Store) AscendRange(start, end []byte, fn func(k, v []byte) error) error {
+	c := s.bucket.Cursor()
+
+	var k, v []byte
+	if len(start) == 0 {
+		k, v = c.First()
+	} else {
+		k, v = c.Seek(start)
+	}
+
+	for ; k != nil; k, v = c.Next() {
+		if end != nil && bytes.Compare(k, end) > 0 {
+			return nil
+		}
+		ck, cv := cloneKV(k, v)
+		if err := fn(ck, cv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DescendRange seeks for end and goes through all the subsequent key value pairs whose key is
+// greater than or equal to start, in decreasing order, calling fn for each pair. If end is nil,
+// starts from the end. If start is nil, there is no lower bound.
+func (s *Store) DescendRange(start, end []byte, fn func(k, v []byte) error) error {
+	c := s.bucket.Cursor()
+
+	var k, v []byte
+	if len(end) == 0 {
+		k, v = c.Last()
+	} else {
+		k, v = c.Seek(end)
+		if k == nil {
+			k, v = c.Last()
+		} else if !bytes.Equal(k, end) {
+			k, v = c.Prev()
+		}
+	}
+
+	for ; k != nil; k, v = c.Prev() {
+		if start != nil && bytes.Compare(k, start) < 0 {
+			return nil
+		}
+		ck, cv := cloneKV(k, v)
+		if err := fn(ck, cv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AscendPrefix calls fn, in increasing order, for every key value pair whose key starts with
+// prefix.
+func (s *Store) AscendPrefix(prefix []byte, fn func(k, v []byte) error) error {
+	c := s.bucket.Cursor()
+
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		ck, cv := cloneKV(k, v)
+		if err := fn(ck, cv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DescendPrefix calls fn, in decreasing order, for every key value pair whose key starts with
+// prefix.
+func (s *Store) DescendPrefix(prefix []byte, fn func(k, v []byte) error) error {
+	return s.DescendRange(prefix, engine.PrefixEnd(prefix), func(k, v []byte) error {
+		if !bytes.HasPrefix(k, prefix) {
+			return nil
+		}
+		return fn(k, v)
+	})
+}
@@ -0,0 +1,93 @@
+package bolt
+
+import (
+	"bytes"
+
+	"github.com/asdine/genji/engine"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Transaction wraps a Bolt transaction and implements the engine.Transaction interface.
+type Transaction struct {
+	tx       *bolt.Tx
+	writable bool
+}
+
+// Rollback the transaction. Can be called safely after a Commit.
+func (t *Transaction) Rollback() error {
+	err := t.tx.Rollback()
+	if err != nil && err != bolt.ErrTxClosed {
+		return err
+	}
+
+	return nil
+}
+
+// Commit the transaction. Returns engine.ErrTransactionReadOnly if the transaction is read-only.
+func (t *Transaction) Commit() error {
+	if !t.writable {
+		return engine.ErrTransactionReadOnly
+	}
+
+	return t.tx.Commit()
+}
+
+// Store returns the store whose name equals name. Returns engine.ErrStoreNotFound if the
+// underlying bucket doesn't exist.
+func (t *Transaction) Store(name string) (engine.Store, error) {
+	b := t.tx.Bucket([]byte(name))
+	if b == nil {
+		return nil, engine.ErrStoreNotFound
+	}
+
+	return &Store{
+		bucket: b,
+		tx:     t.tx,
+	}, nil
+}
+
+// CreateStore creates a bucket with the given name. Returns engine.ErrTransactionReadOnly if the
+// transaction is read-only and engine.ErrStoreAlreadyExists if the bucket already exists.
+func (t *Transaction) CreateStore(name string) error {
+	if !t.writable {
+		return engine.ErrTransactionReadOnly
+	}
+
+	_, err := t.tx.CreateBucket([]byte(name))
+	if err == bolt.ErrBucketExists {
+		return engine.ErrStoreAlreadyExists
+	}
+
+	return err
+}
+
+// DropStore deletes the bucket whose name equals name. Returns engine.ErrTransactionReadOnly if
+// the transaction is read-only and engine.ErrStoreNotFound if the bucket doesn't exist.
+func (t *Transaction) DropStore(name string) error {
+	if !t.writable {
+		return engine.ErrTransactionReadOnly
+	}
+
+	err := t.tx.DeleteBucket([]byte(name))
+	if err == bolt.ErrBucketNotFound {
+		return engine.ErrStoreNotFound
+	}
+
+	return err
+}
+
+// StoreList returns the list of bucket names that start with prefix, in lexicographic order.
+func (t *Transaction) StoreList(prefix string) ([]string, error) {
+	var list []string
+
+	p := []byte(prefix)
+	c := t.tx.Cursor()
+	for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+		// Cursor.Seek returns a nil value for keys pointing to buckets.
+		if v == nil {
+			list = append(list, string(k))
+		}
+	}
+
+	return list, nil
+}
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"errors"
+	"io"
 )
 
 // Common errors returned by the engine implementations.
@@ -29,6 +30,17 @@ type Engine interface {
 	Close() error
 }
 
+// Snapshotter is an optional interface that an Engine implementation can satisfy to provide its
+// own, more efficient way of taking and loading a snapshot of all of its stores. Engines that
+// don't implement it can still be snapshotted through the generic implementation in the
+// engine/snapshot package, which drives the Engine interface directly.
+type Snapshotter interface {
+	// Snapshot writes a consistent copy of every store and key value pair to w.
+	Snapshot(w io.Writer) error
+	// Restore replaces the engine's content with the data previously written by Snapshot.
+	Restore(r io.Reader) error
+}
+
 // A Transaction provides methods for managing the collection of stores and the transaction itself.
 // The transaction is either read-only or read/write. Read-only transactions can be used to read stores
 // and read/write ones can be used to read, create, delete and modify stores.
@@ -59,4 +71,16 @@ type Store interface {
 	// If the given function returns an error, the iteration stops and returns that error.
 	// If the pivot is nil, starts from the end.
 	DescendLessOrEqual(pivot []byte, fn func(k, v []byte) error) error
+	// AscendRange seeks for start and goes through all the subsequent key value pairs whose key is
+	// lesser than or equal to end, in increasing order, calling fn for each pair.
+	// If start is nil, starts from the beginning. If end is nil, there is no upper bound.
+	AscendRange(start, end []byte, fn func(k, v []byte) error) error
+	// DescendRange seeks for end and goes through all the subsequent key value pairs whose key is
+	// greater than or equal to start, in decreasing order, calling fn for each pair.
+	// If end is nil, starts from the end. If start is nil, there is no lower bound.
+	DescendRange(start, end []byte, fn func(k, v []byte) error) error
+	// AscendPrefix calls fn, in increasing order, for every key value pair whose key starts with prefix.
+	AscendPrefix(prefix []byte, fn func(k, v []byte) error) error
+	// DescendPrefix calls fn, in decreasing order, for every key value pair whose key starts with prefix.
+	DescendPrefix(prefix []byte, fn func(k, v []byte) error) error
 }
@@ -0,0 +1,96 @@
+// Package enginetest provides shared test helpers that exercise any engine.Store implementation
+// through its exported interface, so that every backend is tested against the exact same fixtures
+// and can't silently drift from one another.
+package enginetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// storeIterator is the subset of engine.Store exercised by TestStoreAscendDescend and
+// TestStoreRangeAndPrefix.
+type storeIterator interface {
+	AscendGreaterOrEqual(pivot []byte, fn func(k, v []byte) error) error
+	DescendLessOrEqual(pivot []byte, fn func(k, v []byte) error) error
+	AscendRange(start, end []byte, fn func(k, v []byte) error) error
+	DescendRange(start, end []byte, fn func(k, v []byte) error) error
+	AscendPrefix(prefix []byte, fn func(k, v []byte) error) error
+	DescendPrefix(prefix []byte, fn func(k, v []byte) error) error
+}
+
+// storeWriter is the subset of engine.Store needed to seed the fixtures below.
+type storeWriter interface {
+	Put(k, v []byte) error
+}
+
+// TestStoreAscendDescend puts the fixture keys "a", "b", "c", "d" into st and exercises
+// AscendGreaterOrEqual and DescendLessOrEqual against them.
+func TestStoreAscendDescend(t *testing.T, st interface {
+	storeWriter
+	storeIterator
+}) {
+	for _, k := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, st.Put([]byte(k), []byte(k)))
+	}
+
+	var got []string
+	err := st.AscendGreaterOrEqual([]byte("b"), func(k, v []byte) error {
+		got = append(got, string(k))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c", "d"}, got)
+
+	got = nil
+	err = st.DescendLessOrEqual([]byte("c"), func(k, v []byte) error {
+		got = append(got, string(k))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"c", "b", "a"}, got)
+}
+
+// TestStoreRangeAndPrefix puts the fixture keys "a/1", "a/2", "a/3", "b/1", "c/1" into st and
+// exercises AscendRange, DescendRange, AscendPrefix and DescendPrefix against them.
+func TestStoreRangeAndPrefix(t *testing.T, st interface {
+	storeWriter
+	storeIterator
+}) {
+	for _, k := range []string{"a/1", "a/2", "a/3", "b/1", "c/1"} {
+		require.NoError(t, st.Put([]byte(k), []byte(k)))
+	}
+
+	var got []string
+	err := st.AscendRange([]byte("a/2"), []byte("b/1"), func(k, v []byte) error {
+		got = append(got, string(k))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/2", "a/3", "b/1"}, got)
+
+	got = nil
+	err = st.DescendRange([]byte("a/2"), []byte("b/1"), func(k, v []byte) error {
+		got = append(got, string(k))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b/1", "a/3", "a/2"}, got)
+
+	got = nil
+	err = st.AscendPrefix([]byte("a/"), func(k, v []byte) error {
+		got = append(got, string(k))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/1", "a/2", "a/3"}, got)
+
+	got = nil
+	err = st.DescendPrefix([]byte("a/"), func(k, v []byte) error {
+		got = append(got, string(k))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/3", "a/2", "a/1"}, got)
+}
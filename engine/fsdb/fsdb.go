@@ -0,0 +1,85 @@
+// Package fsdb implements an engine that stores each store as a directory and each key value
+// pair as a file. It has no dependencies outside of the standard library, which makes it a good
+// fit for embedded use cases and CLI tools where a single-file database engine is overkill.
+package fsdb
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/asdine/genji/engine"
+)
+
+// txDirPrefix marks the temporary staging directories created for writable transactions so that
+// StoreList can skip them.
+const txDirPrefix = ".fsdb-tx-"
+
+// Engine is an engine.Engine implementation that stores its data on disk, below root.
+// Each store is a directory and each key value pair is a file within that directory, named after
+// the hex encoding of the key so that directory listings come back in key order.
+type Engine struct {
+	root string
+	mu   sync.RWMutex
+}
+
+// NewEngine creates an engine that stores its data below root, creating the directory if it
+// doesn't exist yet.
+func NewEngine(root string) (*Engine, error) {
+	err := os.MkdirAll(root, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Engine{root: root}, nil
+}
+
+// Begin starts a transaction. Writable transactions take the engine's write lock and get a
+// private staging directory; read-only transactions take the read lock and read directly from
+// the store directories.
+func (ng *Engine) Begin(writable bool) (engine.Transaction, error) {
+	if writable {
+		ng.mu.Lock()
+	} else {
+		ng.mu.RLock()
+	}
+
+	var staging string
+	if writable {
+		dir, err := ioutil.TempDir(ng.root, txDirPrefix)
+		if err != nil {
+			ng.mu.Unlock()
+			return nil, err
+		}
+		staging = dir
+	}
+
+	return &Transaction{
+		ng:       ng,
+		writable: writable,
+		staging:  staging,
+	}, nil
+}
+
+// Close does nothing, as the fsdb engine keeps no file descriptors open between transactions.
+func (ng *Engine) Close() error {
+	return nil
+}
+
+// encodeKey returns the filename used to store k. Hex encoding maps every byte to exactly two
+// characters in a way that preserves byte ordering, so a sorted directory listing of encoded
+// keys is also sorted by the original keys.
+func encodeKey(k []byte) string {
+	return hex.EncodeToString(k)
+}
+
+// decodeKey reverses encodeKey.
+func decodeKey(name string) ([]byte, error) {
+	return hex.DecodeString(name)
+}
+
+func storeDir(root, name string) string {
+	return filepath.Join(root, name)
+}
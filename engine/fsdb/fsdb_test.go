@@ -0,0 +1,187 @@
+package fsdb_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/asdine/genji/engine"
+	"github.com/asdine/genji/engine/enginetest"
+	"github.com/asdine/genji/engine/fsdb"
+	"github.com/stretchr/testify/require"
+)
+
+func tempEngine(t *testing.T) (*fsdb.Engine, func()) {
+	dir, err := ioutil.TempDir("", "genji-fsdb")
+	require.NoError(t, err)
+
+	ng, err := fsdb.NewEngine(dir)
+	require.NoError(t, err)
+
+	return ng, func() {
+		ng.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestEngineStores(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+
+	require.NoError(t, tx.CreateStore("table1"))
+	require.Equal(t, engine.ErrStoreAlreadyExists, tx.CreateStore("table1"))
+
+	_, err = tx.Store("table2")
+	require.Equal(t, engine.ErrStoreNotFound, err)
+
+	require.NoError(t, tx.Commit())
+}
+
+func TestStoreListOrder(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("b"))
+	require.NoError(t, tx.CreateStore("d"))
+	require.NoError(t, tx.Commit())
+
+	tx, err = ng.Begin(true)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	require.NoError(t, tx.CreateStore("c"))
+
+	list, err := tx.StoreList("")
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c", "d"}, list)
+}
+
+func TestStorePutGetDelete(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+
+	_, err = st.Get([]byte("foo"))
+	require.Equal(t, engine.ErrKeyNotFound, err)
+
+	require.NoError(t, st.Put([]byte("foo"), []byte("bar")))
+
+	v, err := st.Get([]byte("foo"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), v)
+
+	require.NoError(t, tx.Commit())
+
+	// changes must survive the transaction that wrote them.
+	tx, err = ng.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	st, err = tx.Store("table1")
+	require.NoError(t, err)
+
+	v, err = st.Get([]byte("foo"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), v)
+}
+
+func TestRollbackDiscardsChanges(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	require.NoError(t, tx.Commit())
+
+	tx, err = ng.Begin(true)
+	require.NoError(t, err)
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+	require.NoError(t, st.Put([]byte("foo"), []byte("bar")))
+	require.NoError(t, tx.Rollback())
+
+	tx, err = ng.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	st, err = tx.Store("table1")
+	require.NoError(t, err)
+	_, err = st.Get([]byte("foo"))
+	require.Equal(t, engine.ErrKeyNotFound, err)
+}
+
+func TestRollbackDiscardsStoreCreation(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	require.NoError(t, tx.Rollback())
+
+	tx, err = ng.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	_, err = tx.Store("table1")
+	require.Equal(t, engine.ErrStoreNotFound, err)
+}
+
+func TestRollbackDiscardsStoreDrop(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	require.NoError(t, tx.Commit())
+
+	tx, err = ng.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.DropStore("table1"))
+	require.NoError(t, tx.Rollback())
+
+	tx, err = ng.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	_, err = tx.Store("table1")
+	require.NoError(t, err)
+}
+
+func TestStoreAscendDescend(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+
+	enginetest.TestStoreAscendDescend(t, st)
+}
+
+func TestStoreRangeAndPrefix(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+
+	enginetest.TestStoreRangeAndPrefix(t, st)
+}
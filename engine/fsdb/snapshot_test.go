@@ -0,0 +1,43 @@
+package fsdb_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineSnapshotRestore(t *testing.T) {
+	ng, cleanup := tempEngine(t)
+	defer cleanup()
+
+	tx, err := ng.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+	require.NoError(t, st.Put([]byte("foo"), []byte("bar")))
+	require.NoError(t, tx.CreateStore("empty"))
+	require.NoError(t, tx.Commit())
+
+	var buf bytes.Buffer
+	require.NoError(t, ng.Snapshot(&buf))
+
+	dst, cleanup2 := tempEngine(t)
+	defer cleanup2()
+
+	require.NoError(t, dst.Restore(bytes.NewReader(buf.Bytes())))
+
+	tx, err = dst.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	st, err = tx.Store("table1")
+	require.NoError(t, err)
+	v, err := st.Get([]byte("foo"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("bar"), v)
+
+	_, err = tx.Store("empty")
+	require.NoError(t, err)
+}
@@ -0,0 +1,332 @@
+package fsdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/asdine/genji/engine"
+)
+
+// Store implements the engine.Store interface on top of a directory. Every key value pair is a
+// file named after the hex encoding of the key; pending writes of a writable transaction live in
+// a sibling staging directory until Commit.
+type Store struct {
+	dir      string
+	staging  string
+	writable bool
+}
+
+// Get returns the value associated with k. Returns engine.ErrKeyNotFound if it doesn't exist.
+func (s *Store) Get(k []byte) ([]byte, error) {
+	name := encodeKey(k)
+
+	if s.staging != "" {
+		if _, err := os.Stat(filepath.Join(s.staging, name+deletedSuffix)); err == nil {
+			return nil, engine.ErrKeyNotFound
+		}
+		if v, err := ioutil.ReadFile(filepath.Join(s.staging, name)); err == nil {
+			return v, nil
+		}
+		// a staged Truncate hides every key of the committed directory.
+		if _, err := os.Stat(filepath.Join(s.staging, truncateMarker)); err == nil {
+			return nil, engine.ErrKeyNotFound
+		}
+	}
+
+	v, err := ioutil.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, engine.ErrKeyNotFound
+		}
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// Put stores a key value pair, overriding any existing value. Returns
+// engine.ErrTransactionReadOnly if the store belongs to a read-only transaction.
+func (s *Store) Put(k, v []byte) error {
+	if !s.writable {
+		return engine.ErrTransactionReadOnly
+	}
+
+	name := encodeKey(k)
+	if err := os.Remove(filepath.Join(s.staging, name+deletedSuffix)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(s.staging, name), v, 0644)
+}
+
+// Delete a key value pair. Returns engine.ErrKeyNotFound if the key doesn't exist and
+// engine.ErrTransactionReadOnly if the store belongs to a read-only transaction.
+func (s *Store) Delete(k []byte) error {
+	if !s.writable {
+		return engine.ErrTransactionReadOnly
+	}
+
+	if _, err := s.Get(k); err != nil {
+		return err
+	}
+
+	name := encodeKey(k)
+	if err := os.Remove(filepath.Join(s.staging, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(s.staging, name+deletedSuffix), nil, 0644)
+}
+
+// Truncate deletes all the key value pairs from the store.
+func (s *Store) Truncate() error {
+	if !s.writable {
+		return engine.ErrTransactionReadOnly
+	}
+
+	entries, err := ioutil.ReadDir(s.staging)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		// keep the staged creation or removal of the store itself.
+		switch e.Name() {
+		case createMarker, dropMarker:
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.staging, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(filepath.Join(s.staging, truncateMarker))
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// visibleKeys returns the sorted, deduplicated list of encoded key names currently visible
+// through this store, taking staged puts and deletes into account.
+func (s *Store) visibleKeys() ([]string, error) {
+	seen := make(map[string]bool)
+	var keys []string
+
+	truncated := false
+	if s.staging != "" {
+		if _, err := os.Stat(filepath.Join(s.staging, truncateMarker)); err == nil {
+			truncated = true
+		}
+	}
+
+	if !truncated {
+		entries, err := ioutil.ReadDir(s.dir)
+		if err != nil {
+			// the store's real directory may not exist yet if it was only created earlier
+			// within the same uncommitted transaction; treat that as an empty store.
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+		} else {
+			for _, e := range entries {
+				keys = append(keys, e.Name())
+				seen[e.Name()] = true
+			}
+		}
+	}
+
+	if s.staging != "" {
+		entries, err := ioutil.ReadDir(s.staging)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			name := e.Name()
+			switch name {
+			case createMarker, dropMarker, truncateMarker:
+				continue
+			}
+			if strings.HasSuffix(name, deletedSuffix) {
+				key := strings.TrimSuffix(name, deletedSuffix)
+				if seen[key] {
+					seen[key] = false
+				}
+				continue
+			}
+			if !seen[name] {
+				keys = append(keys, name)
+			}
+			seen[name] = true
+		}
+	}
+
+	out := keys[:0]
+	for _, k := range keys {
+		if seen[k] {
+			out = append(out, k)
+		}
+	}
+
+	sort.Strings(out)
+	return out, nil
+}
+
+// AscendGreaterOrEqual seeks for the pivot and goes through all the subsequent key value pairs in
+// increasing order, calling fn for each pair. If pivot is nil, starts from the beginning.
+func (s *Store) AscendGreaterOrEqual(pivot []byte, fn func(k, v []byte) error) error {
+	keys, err := s.visibleKeys()
+	if err != nil {
+		return err
+	}
+
+	from := encodeKey(pivot)
+	i := sort.SearchStrings(keys, from)
+
+	for ; i < len(keys); i++ {
+		if err := s.emit(keys[i], fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DescendLessOrEqual seeks for the pivot and goes through all the subsequent key value pairs in
+// decreasing order, calling fn for each pair. If pivot is nil, starts from the end.
+func (s *Store) DescendLessOrEqual(pivot []byte, fn func(k, v []byte) error) error {
+	keys, err := s.visibleKeys()
+	if err != nil {
+		return err
+	}
+
+	i := len(keys) - 1
+	if len(pivot) > 0 {
+		to := encodeKey(pivot)
+		i = sort.SearchStrings(keys, to)
+		if i == len(keys) || keys[i] != to {
+			i--
+		}
+	}
+
+	for ; i >= 0; i-- {
+		if err := s.emit(keys[i], fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AscendRange seeks for start and goes through all the subsequent key value pairs whose key is
+// lesser than or equal to end, in increasing order, calling fn for each pair. If start is nil,
+// starts from the beginning. If end is nil, there is no upper bound.
+func (s *Store) AscendRange(start, end []byte, fn func(k, v []byte) error) error {
+	keys, err := s.visibleKeys()
+	if err != nil {
+		return err
+	}
+
+	i := sort.SearchStrings(keys, encodeKey(start))
+
+	for ; i < len(keys); i++ {
+		if end != nil && keys[i] > encodeKey(end) {
+			return nil
+		}
+		if err := s.emit(keys[i], fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DescendRange seeks for end and goes through all the subsequent key value pairs whose key is
+// greater than or equal to start, in decreasing order, calling fn for each pair. If end is nil,
+// starts from the end. If start is nil, there is no lower bound.
+func (s *Store) DescendRange(start, end []byte, fn func(k, v []byte) error) error {
+	keys, err := s.visibleKeys()
+	if err != nil {
+		return err
+	}
+
+	i := len(keys) - 1
+	if len(end) > 0 {
+		to := encodeKey(end)
+		i = sort.SearchStrings(keys, to)
+		if i == len(keys) || keys[i] != to {
+			i--
+		}
+	}
+
+	for ; i >= 0; i-- {
+		if start != nil && keys[i] < encodeKey(start) {
+			return nil
+		}
+		if err := s.emit(keys[i], fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AscendPrefix calls fn, in increasing order, for every key value pair whose key starts with
+// prefix.
+func (s *Store) AscendPrefix(prefix []byte, fn func(k, v []byte) error) error {
+	keys, err := s.visibleKeys()
+	if err != nil {
+		return err
+	}
+
+	encoded := encodeKey(prefix)
+	i := sort.SearchStrings(keys, encoded)
+
+	for ; i < len(keys) && strings.HasPrefix(keys[i], encoded); i++ {
+		if err := s.emit(keys[i], fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DescendPrefix calls fn, in decreasing order, for every key value pair whose key starts with
+// prefix.
+func (s *Store) DescendPrefix(prefix []byte, fn func(k, v []byte) error) error {
+	keys, err := s.visibleKeys()
+	if err != nil {
+		return err
+	}
+
+	encoded := encodeKey(prefix)
+	i := sort.SearchStrings(keys, encoded)
+	last := i - 1
+	for ; i < len(keys) && strings.HasPrefix(keys[i], encoded); i++ {
+		last = i
+	}
+
+	for ; last >= 0 && strings.HasPrefix(keys[last], encoded); last-- {
+		if err := s.emit(keys[last], fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) emit(name string, fn func(k, v []byte) error) error {
+	k, err := decodeKey(name)
+	if err != nil {
+		return err
+	}
+
+	v, err := s.Get(k)
+	if err != nil {
+		return err
+	}
+
+	return fn(k, v)
+}
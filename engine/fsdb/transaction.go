@@ -0,0 +1,256 @@
+package fsdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/asdine/genji/engine"
+)
+
+// Marker file names used within a transaction's staging directory. None of them can collide with
+// an encoded key, because hex-encoded keys never contain a dot.
+const (
+	createMarker   = ".create"
+	dropMarker     = ".drop"
+	truncateMarker = ".truncate"
+	deletedSuffix  = ".del"
+)
+
+// Transaction implements the engine.Transaction interface on top of a directory tree.
+// Writable transactions stage every mutation, including store creation and removal, below a
+// private directory and only apply it to the real store directories on Commit; Rollback simply
+// discards that directory, leaving the engine's root untouched.
+type Transaction struct {
+	ng       *Engine
+	writable bool
+	staging  string
+	closed   bool
+}
+
+// Rollback discards any staged change and releases the engine lock taken by Begin.
+func (t *Transaction) Rollback() error {
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+
+	if t.writable {
+		defer t.ng.mu.Unlock()
+		return os.RemoveAll(t.staging)
+	}
+
+	t.ng.mu.RUnlock()
+	return nil
+}
+
+// Commit applies the staged stores and key value pairs to the engine's root directory and
+// releases the write lock. Returns engine.ErrTransactionReadOnly if the transaction is read-only.
+func (t *Transaction) Commit() error {
+	if !t.writable {
+		return engine.ErrTransactionReadOnly
+	}
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	defer t.ng.mu.Unlock()
+
+	entries, err := ioutil.ReadDir(t.staging)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		storeStaging := filepath.Join(t.staging, name)
+
+		if _, err := os.Stat(filepath.Join(storeStaging, dropMarker)); err == nil {
+			if err := os.RemoveAll(storeDir(t.ng.root, name)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		dst := storeDir(t.ng.root, name)
+		if _, err := os.Stat(filepath.Join(storeStaging, createMarker)); err == nil {
+			if err := os.MkdirAll(dst, 0755); err != nil {
+				return err
+			}
+		}
+
+		if err := mergeStore(storeStaging, dst); err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(t.staging)
+}
+
+// mergeStore applies the staged files of a single store onto its real directory.
+func mergeStore(staging, dst string) error {
+	if _, err := os.Stat(filepath.Join(staging, truncateMarker)); err == nil {
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dst, 0755); err != nil {
+			return err
+		}
+	}
+
+	entries, err := ioutil.ReadDir(staging)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		switch name {
+		case createMarker, dropMarker, truncateMarker:
+			continue
+		}
+
+		if strings.HasSuffix(name, deletedSuffix) {
+			key := strings.TrimSuffix(name, deletedSuffix)
+			if err := os.Remove(filepath.Join(dst, key)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+
+		if err := os.Rename(filepath.Join(staging, name), filepath.Join(dst, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Store returns the store whose name equals name. Returns engine.ErrStoreNotFound if it doesn't
+// exist, taking any staged create/drop into account. The store's real directory may not exist yet
+// if it was created earlier within the same uncommitted transaction; Store's methods tolerate
+// that and behave as if it were empty.
+func (t *Transaction) Store(name string) (engine.Store, error) {
+	if !t.storeExists(name) {
+		return nil, engine.ErrStoreNotFound
+	}
+
+	s := &Store{
+		dir:      storeDir(t.ng.root, name),
+		writable: t.writable,
+	}
+	if t.writable {
+		s.staging = filepath.Join(t.staging, name)
+		if err := os.MkdirAll(s.staging, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (t *Transaction) storeExists(name string) bool {
+	if t.writable {
+		staging := filepath.Join(t.staging, name)
+		if _, err := os.Stat(filepath.Join(staging, dropMarker)); err == nil {
+			return false
+		}
+		if _, err := os.Stat(filepath.Join(staging, createMarker)); err == nil {
+			return true
+		}
+	}
+
+	_, err := os.Stat(storeDir(t.ng.root, name))
+	return err == nil
+}
+
+// CreateStore stages the creation of a directory to hold the store's key value pairs; the
+// directory itself is only created on Commit. Returns engine.ErrTransactionReadOnly if the
+// transaction is read-only and engine.ErrStoreAlreadyExists if the store already exists.
+func (t *Transaction) CreateStore(name string) error {
+	if !t.writable {
+		return engine.ErrTransactionReadOnly
+	}
+
+	if t.storeExists(name) {
+		return engine.ErrStoreAlreadyExists
+	}
+
+	staging := filepath.Join(t.staging, name)
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(staging, createMarker))
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// DropStore stages the removal of the store's directory; the directory itself is only deleted on
+// Commit. Returns engine.ErrTransactionReadOnly if the transaction is read-only and
+// engine.ErrStoreNotFound if the store doesn't exist.
+func (t *Transaction) DropStore(name string) error {
+	if !t.writable {
+		return engine.ErrTransactionReadOnly
+	}
+
+	if !t.storeExists(name) {
+		return engine.ErrStoreNotFound
+	}
+
+	staging := filepath.Join(t.staging, name)
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(staging, dropMarker))
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// StoreList returns the list of store names that start with prefix, in lexicographic order.
+func (t *Transaction) StoreList(prefix string) ([]string, error) {
+	entries, err := ioutil.ReadDir(t.ng.root)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || strings.HasPrefix(name, txDirPrefix) || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if t.writable && !t.storeExists(name) {
+			continue
+		}
+		seen[name] = true
+		list = append(list, name)
+	}
+
+	if t.writable {
+		staged, err := ioutil.ReadDir(t.staging)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range staged {
+			name := e.Name()
+			if seen[name] || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if t.storeExists(name) {
+				list = append(list, name)
+			}
+		}
+	}
+
+	sort.Strings(list)
+	return list, nil
+}
@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"bytes"
+	"errors"
+)
+
+// errStop is used internally to unwind out of AscendGreaterOrEqual/DescendLessOrEqual once a
+// Default* helper below has reached the end of the range it was asked to cover.
+var errStop = errors.New("engine: stop iteration")
+
+// DefaultAscendRange is a generic implementation of Store.AscendRange built on top of
+// AscendGreaterOrEqual. Engine implementations whose underlying storage can't be told about an
+// upper bound natively can use it to satisfy the Store interface.
+func DefaultAscendRange(s Store, start, end []byte, fn func(k, v []byte) error) error {
+	err := s.AscendGreaterOrEqual(start, func(k, v []byte) error {
+		if end != nil && bytes.Compare(k, end) > 0 {
+			return errStop
+		}
+		return fn(k, v)
+	})
+	if err == errStop {
+		return nil
+	}
+	return err
+}
+
+// DefaultDescendRange is a generic implementation of Store.DescendRange built on top of
+// DescendLessOrEqual. Engine implementations whose underlying storage can't be told about a lower
+// bound natively can use it to satisfy the Store interface.
+func DefaultDescendRange(s Store, start, end []byte, fn func(k, v []byte) error) error {
+	err := s.DescendLessOrEqual(end, func(k, v []byte) error {
+		if start != nil && bytes.Compare(k, start) < 0 {
+			return errStop
+		}
+		return fn(k, v)
+	})
+	if err == errStop {
+		return nil
+	}
+	return err
+}
+
+// DefaultAscendPrefix is a generic implementation of Store.AscendPrefix built on top of
+// AscendGreaterOrEqual.
+func DefaultAscendPrefix(s Store, prefix []byte, fn func(k, v []byte) error) error {
+	err := s.AscendGreaterOrEqual(prefix, func(k, v []byte) error {
+		if !bytes.HasPrefix(k, prefix) {
+			return errStop
+		}
+		return fn(k, v)
+	})
+	if err == errStop {
+		return nil
+	}
+	return err
+}
+
+// DefaultDescendPrefix is a generic implementation of Store.DescendPrefix built on top of
+// DescendLessOrEqual.
+func DefaultDescendPrefix(s Store, prefix []byte, fn func(k, v []byte) error) error {
+	err := s.DescendLessOrEqual(PrefixEnd(prefix), func(k, v []byte) error {
+		if bytes.HasPrefix(k, prefix) {
+			return fn(k, v)
+		}
+		// PrefixEnd is an exclusive bound: if it matches an actual key verbatim, skip over it
+		// rather than stopping, since smaller keys may still be within the prefix range.
+		if bytes.Compare(k, prefix) < 0 {
+			return errStop
+		}
+		return nil
+	})
+	if err == errStop {
+		return nil
+	}
+	return err
+}
+
+// PrefixEnd returns the smallest key that is strictly greater than every key starting with
+// prefix, or nil if there is no such key (prefix is empty or made entirely of 0xFF bytes).
+// It is commonly used as the exclusive upper bound of a prefix scan.
+func PrefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] != 0xFF {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+
+	return nil
+}
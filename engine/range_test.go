@@ -0,0 +1,111 @@
+package engine_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/asdine/genji/engine"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceStore is a minimal engine.Store backed by a sorted slice, used to exercise the Default*
+// range helpers without depending on a concrete engine implementation.
+type sliceStore struct {
+	keys   []string
+	values map[string]string
+}
+
+func newSliceStore(keys ...string) *sliceStore {
+	sort.Strings(keys)
+	return &sliceStore{keys: keys}
+}
+
+func (s *sliceStore) AscendGreaterOrEqual(pivot []byte, fn func(k, v []byte) error) error {
+	i := sort.SearchStrings(s.keys, string(pivot))
+	for ; i < len(s.keys); i++ {
+		if err := fn([]byte(s.keys[i]), []byte(s.keys[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sliceStore) DescendLessOrEqual(pivot []byte, fn func(k, v []byte) error) error {
+	i := len(s.keys) - 1
+	if len(pivot) > 0 {
+		p := string(pivot)
+		i = sort.SearchStrings(s.keys, p)
+		if i == len(s.keys) || s.keys[i] != p {
+			i--
+		}
+	}
+	for ; i >= 0; i-- {
+		if err := fn([]byte(s.keys[i]), []byte(s.keys[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sliceStore) Get(k []byte) ([]byte, error) { panic("not implemented") }
+func (s *sliceStore) Put(k, v []byte) error        { panic("not implemented") }
+func (s *sliceStore) Delete(k []byte) error        { panic("not implemented") }
+func (s *sliceStore) Truncate() error              { panic("not implemented") }
+func (s *sliceStore) AscendRange(a, b []byte, fn func(k, v []byte) error) error {
+	panic("not implemented")
+}
+func (s *sliceStore) DescendRange(a, b []byte, fn func(k, v []byte) error) error {
+	panic("not implemented")
+}
+func (s *sliceStore) AscendPrefix(p []byte, fn func(k, v []byte) error) error {
+	panic("not implemented")
+}
+func (s *sliceStore) DescendPrefix(p []byte, fn func(k, v []byte) error) error {
+	panic("not implemented")
+}
+
+func TestDefaultAscendDescendRange(t *testing.T) {
+	s := newSliceStore("a/1", "a/2", "a/3", "b/1", "c/1")
+
+	var got []string
+	err := engine.DefaultAscendRange(s, []byte("a/2"), []byte("b/1"), func(k, v []byte) error {
+		got = append(got, string(k))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/2", "a/3", "b/1"}, got)
+
+	got = nil
+	err = engine.DefaultDescendRange(s, []byte("a/2"), []byte("b/1"), func(k, v []byte) error {
+		got = append(got, string(k))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b/1", "a/3", "a/2"}, got)
+}
+
+func TestDefaultAscendDescendPrefix(t *testing.T) {
+	s := newSliceStore("a/1", "a/2", "a/3", "b/1", "c/1")
+
+	var got []string
+	err := engine.DefaultAscendPrefix(s, []byte("a/"), func(k, v []byte) error {
+		got = append(got, string(k))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/1", "a/2", "a/3"}, got)
+
+	got = nil
+	err = engine.DefaultDescendPrefix(s, []byte("a/"), func(k, v []byte) error {
+		got = append(got, string(k))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/3", "a/2", "a/1"}, got)
+}
+
+func TestPrefixEnd(t *testing.T) {
+	require.Equal(t, []byte("ac"), engine.PrefixEnd([]byte("ab")))
+	require.Equal(t, []byte{0x01}, engine.PrefixEnd([]byte{0x00, 0xFF}))
+	require.Nil(t, engine.PrefixEnd([]byte{0xFF, 0xFF}))
+}
@@ -0,0 +1,245 @@
+// Package snapshot provides a generic way to serialize the content of any engine.Engine to a
+// single stream and to restore it later, including into a different engine implementation. This
+// makes it possible to take consistent backups and migrate data between backends.
+package snapshot
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+
+	"github.com/asdine/genji/engine"
+)
+
+// ErrInvalidFormat is returned by Restore when the stream doesn't start with the expected magic
+// number or uses a version of the format this package doesn't support.
+var ErrInvalidFormat = errors.New("snapshot: invalid format")
+
+// ErrChecksumMismatch is returned by Restore when the trailing checksum doesn't match the content
+// of the stream, which means it was truncated or corrupted.
+var ErrChecksumMismatch = errors.New("snapshot: checksum mismatch")
+
+// magic identifies the beginning of a stream produced by Snapshot.
+var magic = [4]byte{'G', 'N', 'J', 'S'}
+
+// version of the format written by this package.
+const version = 1
+
+// end is the sentinel record name length that terminates the stream, right before the trailing
+// checksum.
+const end = 0xFFFF
+
+// Snapshot writes a consistent copy of every store and key value pair of ng to w.
+// If ng implements engine.Snapshotter, its native implementation is used. Otherwise, Snapshot
+// iterates over every store with a read-only transaction and streams the content without
+// buffering it all in memory, as a list of every store name (so that a store with no keys is
+// still recreated by Restore) followed by a sequence of length-prefixed {store, key, value}
+// frames, a sentinel frame, and a CRC32 checksum of everything that precedes it.
+func Snapshot(ng engine.Engine, w io.Writer) error {
+	if s, ok := ng.(engine.Snapshotter); ok {
+		return s.Snapshot(w)
+	}
+
+	tx, err := ng.Begin(false)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	h := crc32.NewIEEE()
+	bw := bufio.NewWriter(io.MultiWriter(w, h))
+
+	if _, err := bw.Write(magic[:]); err != nil {
+		return err
+	}
+	if err := bw.WriteByte(version); err != nil {
+		return err
+	}
+
+	stores, err := tx.StoreList("")
+	if err != nil {
+		return err
+	}
+
+	// Every store name is written up front, independent of whether it holds any key value
+	// pairs, so that an empty store still exists after Restore instead of silently vanishing.
+	if err := binary.Write(bw, binary.BigEndian, uint16(len(stores))); err != nil {
+		return err
+	}
+	for _, name := range stores {
+		if err := writeChunk(bw, uint16(len(name)), []byte(name)); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range stores {
+		st, err := tx.Store(name)
+		if err != nil {
+			return err
+		}
+
+		err = st.AscendGreaterOrEqual(nil, func(k, v []byte) error {
+			return writeRecord(bw, name, k, v)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := binary.Write(bw, binary.BigEndian, uint16(end)); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	return binary.Write(w, binary.BigEndian, h.Sum32())
+}
+
+func writeRecord(w io.Writer, store string, k, v []byte) error {
+	if err := writeChunk(w, uint16(len(store)), []byte(store)); err != nil {
+		return err
+	}
+	if err := writeChunk32(w, uint32(len(k)), k); err != nil {
+		return err
+	}
+	return writeChunk32(w, uint32(len(v)), v)
+}
+
+func writeChunk(w io.Writer, n uint16, b []byte) error {
+	if err := binary.Write(w, binary.BigEndian, n); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func writeChunk32(w io.Writer, n uint32, b []byte) error {
+	if err := binary.Write(w, binary.BigEndian, n); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// Restore reads a stream produced by Snapshot and recreates its stores and key value pairs into
+// ng, creating stores that don't exist yet. If ng implements engine.Snapshotter, its native
+// implementation is used instead of the generic format.
+func Restore(ng engine.Engine, r io.Reader) error {
+	if s, ok := ng.(engine.Snapshotter); ok {
+		return s.Restore(r)
+	}
+
+	h := crc32.NewIEEE()
+	tr := io.TeeReader(r, h)
+
+	var gotMagic [4]byte
+	if _, err := io.ReadFull(tr, gotMagic[:]); err != nil {
+		return err
+	}
+	if gotMagic != magic {
+		return ErrInvalidFormat
+	}
+
+	var v uint8
+	if err := binary.Read(tr, binary.BigEndian, &v); err != nil {
+		return err
+	}
+	if v != version {
+		return ErrInvalidFormat
+	}
+
+	tx, err := ng.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var storeCount uint16
+	if err := binary.Read(tr, binary.BigEndian, &storeCount); err != nil {
+		return err
+	}
+	for i := uint16(0); i < storeCount; i++ {
+		name, err := readChunk(tr)
+		if err != nil {
+			return err
+		}
+		if err := tx.CreateStore(string(name)); err != nil && err != engine.ErrStoreAlreadyExists {
+			return err
+		}
+	}
+
+	for {
+		var nameLen uint16
+		if err := binary.Read(tr, binary.BigEndian, &nameLen); err != nil {
+			return err
+		}
+		if nameLen == end {
+			break
+		}
+
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(tr, name); err != nil {
+			return err
+		}
+
+		key, err := readChunk32(tr)
+		if err != nil {
+			return err
+		}
+
+		val, err := readChunk32(tr)
+		if err != nil {
+			return err
+		}
+
+		st, err := tx.Store(string(name))
+		if err != nil {
+			return err
+		}
+		if err := st.Put(key, val); err != nil {
+			return err
+		}
+	}
+
+	sum := h.Sum32()
+	var want uint32
+	if err := binary.Read(r, binary.BigEndian, &want); err != nil {
+		return err
+	}
+	if want != sum {
+		return ErrChecksumMismatch
+	}
+
+	return tx.Commit()
+}
+
+func readChunk(r io.Reader) ([]byte, error) {
+	var n uint16
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func readChunk32(r io.Reader) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
@@ -0,0 +1,132 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/asdine/genji/engine"
+	"github.com/asdine/genji/engine/fsdb"
+	"github.com/asdine/genji/engine/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+// genericEngine wraps an engine.Engine without exposing any Snapshotter methods it might have, so
+// that tests in this package exercise the generic format in engine/snapshot rather than a
+// backend's own native fast path.
+type genericEngine struct {
+	engine.Engine
+}
+
+func newFsdbEngine(t *testing.T) (engine.Engine, func()) {
+	dir, err := ioutil.TempDir("", "genji-snapshot")
+	require.NoError(t, err)
+
+	ng, err := fsdb.NewEngine(dir)
+	require.NoError(t, err)
+
+	return &genericEngine{ng}, func() {
+		ng.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	src, cleanup := newFsdbEngine(t)
+	defer cleanup()
+
+	tx, err := src.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+	require.NoError(t, st.Put([]byte("a"), []byte("1")))
+	require.NoError(t, st.Put([]byte("b"), []byte("2")))
+	require.NoError(t, tx.CreateStore("table2"))
+	st2, err := tx.Store("table2")
+	require.NoError(t, err)
+	require.NoError(t, st2.Put([]byte("x"), []byte("y")))
+	require.NoError(t, tx.Commit())
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Snapshot(src, &buf))
+
+	dst, cleanup2 := newFsdbEngine(t)
+	defer cleanup2()
+
+	require.NoError(t, snapshot.Restore(dst, &buf))
+
+	tx, err = dst.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	st, err = tx.Store("table1")
+	require.NoError(t, err)
+	v, err := st.Get([]byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), v)
+	v, err = st.Get([]byte("b"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("2"), v)
+
+	st2, err = tx.Store("table2")
+	require.NoError(t, err)
+	v, err = st2.Get([]byte("x"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("y"), v)
+}
+
+func TestSnapshotRestorePreservesEmptyStore(t *testing.T) {
+	src, cleanup := newFsdbEngine(t)
+	defer cleanup()
+
+	tx, err := src.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+	require.NoError(t, st.Put([]byte("a"), []byte("1")))
+	require.NoError(t, tx.CreateStore("empty"))
+	require.NoError(t, tx.Commit())
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Snapshot(src, &buf))
+
+	dst, cleanup2 := newFsdbEngine(t)
+	defer cleanup2()
+
+	require.NoError(t, snapshot.Restore(dst, &buf))
+
+	tx, err = dst.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	_, err = tx.Store("empty")
+	require.NoError(t, err)
+}
+
+func TestRestoreChecksumMismatch(t *testing.T) {
+	src, cleanup := newFsdbEngine(t)
+	defer cleanup()
+
+	tx, err := src.Begin(true)
+	require.NoError(t, err)
+	require.NoError(t, tx.CreateStore("table1"))
+	st, err := tx.Store("table1")
+	require.NoError(t, err)
+	require.NoError(t, st.Put([]byte("a"), []byte("1")))
+	require.NoError(t, tx.Commit())
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Snapshot(src, &buf))
+
+	corrupted := buf.Bytes()
+	corrupted[len(corrupted)-1] ^= 0xFF
+
+	dst, cleanup2 := newFsdbEngine(t)
+	defer cleanup2()
+
+	err = snapshot.Restore(dst, bytes.NewReader(corrupted))
+	require.Equal(t, snapshot.ErrChecksumMismatch, err)
+}